@@ -0,0 +1,251 @@
+// Copyright (c) 2017 DG Lab
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+// rpcgen connects to a running elementsd, reads `help <method>` for each
+// method named on the command line (or -all, every method listed by a
+// bare `help`), and writes scaffolding to -out: one untyped wrapper per
+// method (params as interface{}, one generated result struct per method
+// with fields inferred from the "Result:" section of the help text).
+//
+// Scope: this is a discovery aid for a human writing rpc/methods.go by
+// hand, not a code-generation pipeline. It intentionally does not
+// prevent drift between rpc/methods.go and a live daemon's RPC surface:
+// doing that for real would mean running rpcgen (which needs a reachable
+// elementsd) as part of the build or test suite and diffing its output
+// against rpc/methods.go, which would make elementsd a build-time
+// dependency for everyone who checks out this repo — the opposite of
+// what rpc/rpctest (see chunk0-6) is for. So there is no wiring from
+// this tool into `go build`/`go test`, and none is planned; keeping
+// rpc/methods.go in sync with elementsd after a daemon upgrade is a
+// manual step: re-run rpcgen, diff its scaffolding against
+// rpc/methods.go by hand, and update the typed wrappers that changed.
+//
+// The output is a starting point, not a drop-in replacement for
+// rpc/methods.go: the help parser can discover parameter names and
+// result field names/kinds, but it has no way to know that, say,
+// "listunspent" should return the existing UnspentList type rather than
+// a fresh ListunspentResult, or that a param is really an int64 instead
+// of interface{}. Point -out at a scratch file, hand-type the pieces
+// that matter, and merge them into rpc/methods.go yourself.
+//
+// Usage:
+//
+//	rpcgen -url http://127.0.0.1:18332 -user rpcuser -pass rpcpass \
+//	    -out /tmp/rpcgen_out.go listunspent blindrawtransaction issueasset
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/uniblockchain/confidential-assets-demo/src/rpc"
+)
+
+var (
+	flagUrl  = flag.String("url", "http://127.0.0.1:18332", "elementsd RPC url")
+	flagUser = flag.String("user", "", "elementsd RPC user")
+	flagPass = flag.String("pass", "", "elementsd RPC password")
+	flagOut  = flag.String("out", "rpcgen_out.go", "output file (scaffolding only, see package doc)")
+	flagAll  = flag.Bool("all", false, "generate wrappers for every method reported by `help`")
+)
+
+// resultFieldRe matches a line from the "Result:" section of `help
+// <method>`, e.g.:
+//
+//	"txid"            : "hex",      (string) the transaction id
+//	"confirmations"   : n,          (numeric) the number of confirmations
+var resultFieldRe = regexp.MustCompile(`^\s*"?([A-Za-z0-9_]+)"?\s*:.*\((boolean|numeric|string|array|object)\)`)
+
+// usageParamRe pulls bracketed/plain parameter names out of a method's
+// usage line, e.g. `listunspent ( minconf maxconf ["addr",...] includeUnsafe )`.
+var usageParamRe = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+type field struct {
+	Name    string
+	JsonTag string
+	GoType  string
+}
+
+type method struct {
+	Name    string
+	Params  []string
+	Fields  []field
+	IsArray bool
+}
+
+func main() {
+	flag.Parse()
+	client := rpc.NewRpc(*flagUrl, *flagUser, *flagPass)
+
+	names := flag.Args()
+	if *flagAll {
+		all, err := listAllMethods(client)
+		if err != nil {
+			log.Fatalf("rpcgen: listing methods: %v", err)
+		}
+		names = all
+	}
+	if len(names) == 0 {
+		log.Fatal("rpcgen: no methods given; pass method names or -all")
+	}
+	sort.Strings(names)
+
+	var methods []method
+	for _, name := range names {
+		m, err := describeMethod(client, name)
+		if err != nil {
+			log.Printf("rpcgen: skipping %s: %v", name, err)
+			continue
+		}
+		methods = append(methods, m)
+	}
+
+	src := render(methods)
+	out, err := format.Source([]byte(src))
+	if err != nil {
+		// Write the unformatted source so it can still be inspected.
+		out = []byte(src)
+		log.Printf("rpcgen: gofmt failed, writing raw source: %v", err)
+	}
+	if err := os.WriteFile(*flagOut, out, 0644); err != nil {
+		log.Fatalf("rpcgen: writing %s: %v", *flagOut, err)
+	}
+}
+
+func listAllMethods(client *rpc.Rpc) ([]string, error) {
+	help, _, err := client.RequestAndCastString("help")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, line := range strings.Split(help, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "=") {
+			continue
+		}
+		names = append(names, strings.Fields(line)[0])
+	}
+	return names, nil
+}
+
+func describeMethod(client *rpc.Rpc, name string) (method, error) {
+	help, _, err := client.RequestAndCastString("help", name)
+	if err != nil {
+		return method{}, fmt.Errorf("help %s: %v", name, err)
+	}
+	lines := strings.Split(help, "\n")
+	if len(lines) == 0 {
+		return method{}, fmt.Errorf("empty help text")
+	}
+
+	m := method{Name: name}
+	for _, tok := range usageParamRe.FindAllString(lines[0], -1) {
+		if tok == name {
+			continue
+		}
+		m.Params = append(m.Params, tok)
+	}
+
+	inResult := false
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(strings.TrimSpace(line), "Result") {
+			inResult = true
+			if strings.Contains(line, "[") {
+				m.IsArray = true
+			}
+			continue
+		}
+		if !inResult {
+			continue
+		}
+		match := resultFieldRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		m.Fields = append(m.Fields, field{
+			Name:    exportedName(match[1]),
+			JsonTag: match[1],
+			GoType:  goType(match[2]),
+		})
+	}
+	return m, nil
+}
+
+func goType(kind string) string {
+	switch kind {
+	case "boolean":
+		return "bool"
+	case "numeric":
+		return "float64"
+	case "array":
+		return "[]interface{}"
+	case "object":
+		return "map[string]interface{}"
+	default:
+		return "string"
+	}
+}
+
+func exportedName(jsonName string) string {
+	parts := strings.FieldsFunc(jsonName, func(r rune) bool { return r == '_' })
+	for i, p := range parts {
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+func render(methods []method) string {
+	var b strings.Builder
+	b.WriteString("// Scaffolding generated by rpcgen from `help <method>` output.\n")
+	b.WriteString("// This is a starting point: hand-type params/results and merge the\n")
+	b.WriteString("// pieces that matter into rpc/methods.go; do not commit this file as-is.\n\n")
+	b.WriteString("package rpc\n\n")
+	for _, m := range methods {
+		structName := exportedName(m.Name) + "Result"
+		if len(m.Fields) > 0 {
+			fmt.Fprintf(&b, "type %s struct {\n", structName)
+			for _, f := range m.Fields {
+				fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", f.Name, f.GoType, f.JsonTag)
+			}
+			b.WriteString("}\n\n")
+		}
+
+		funcName := exportedName(m.Name)
+		params := make([]string, len(m.Params))
+		args := make([]string, len(m.Params))
+		for i, p := range m.Params {
+			params[i] = fmt.Sprintf("%s interface{}", p)
+			args[i] = p
+		}
+		fmt.Fprintf(&b, "func (rpc *Rpc) %s(%s) (", funcName, strings.Join(params, ", "))
+		switch {
+		case len(m.Fields) > 0 && m.IsArray:
+			fmt.Fprintf(&b, "[]%s, error) {\n", structName)
+		case len(m.Fields) > 0:
+			fmt.Fprintf(&b, "%s, error) {\n", structName)
+		default:
+			b.WriteString("RpcResponse, error) {\n")
+		}
+		fmt.Fprintf(&b, "\tparams := []interface{}{%s}\n", strings.Join(args, ", "))
+		if len(m.Fields) > 0 {
+			if m.IsArray {
+				fmt.Fprintf(&b, "\tvar result []%s\n", structName)
+			} else {
+				fmt.Fprintf(&b, "\tvar result %s\n", structName)
+			}
+			fmt.Fprintf(&b, "\t_, err := rpc.RequestAndUnmarshalResult(&result, %q, params...)\n", m.Name)
+			b.WriteString("\treturn result, err\n")
+		} else {
+			fmt.Fprintf(&b, "\treturn rpc.Request(%q, params...)\n", m.Name)
+		}
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
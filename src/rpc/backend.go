@@ -0,0 +1,53 @@
+// Copyright (c) 2017 DG Lab
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package rpc
+
+import "fmt"
+
+// Backend is the subset of confidential-asset chain RPCs the demo relies
+// on, factored out of Rpc so that callers can select an implementation
+// at runtime instead of hard-coding elementsd calls.
+type Backend interface {
+	ListUnspent(minconf, maxconf int64, addresses []string) (UnspentList, error)
+	GetNewAddress(account string) (string, error)
+	ValidateAddress(address string) (ValidatedAddress, error)
+	BlindRawTransaction(hexstring string, inputBlinder, inputAmount, inputAsset, inputAssetBlinder []string) (string, error)
+	SignRawTransaction(hexstring string) (SignedTransaction, error)
+	SendRawTransaction(hexstring string) (string, error)
+}
+
+// Config holds the connection details needed to construct a Backend.
+type Config struct {
+	Url  string
+	User string
+	Pass string
+	View bool
+}
+
+// BackendCtor builds a Backend from a Config. Implementations register
+// themselves under a name with Register, typically from an init func.
+type BackendCtor func(cfg Config) (Backend, error)
+
+var backends = make(map[string]BackendCtor)
+
+// Register adds a named backend constructor to the registry. It panics
+// on a duplicate name, since that indicates two backend packages were
+// compiled in under the same name.
+func Register(name string, ctor BackendCtor) {
+	if _, exists := backends[name]; exists {
+		panic(fmt.Sprintf("rpc: backend %q already registered", name))
+	}
+	backends[name] = ctor
+}
+
+// NewBackend looks up the backend registered under name and constructs
+// it with cfg.
+func NewBackend(name string, cfg Config) (Backend, error) {
+	ctor, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("rpc: no backend registered under %q", name)
+	}
+	return ctor(cfg)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2017 DG Lab
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package rpc
+
+func init() {
+	Register("elements", newElementsBackend)
+}
+
+// elementsBackend implements Backend on top of an elementsd JSON-RPC
+// connection, reusing the existing Rpc/methods.go wrappers.
+type elementsBackend struct {
+	rpc *Rpc
+}
+
+func newElementsBackend(cfg Config) (Backend, error) {
+	rpc := NewRpc(cfg.Url, cfg.User, cfg.Pass)
+	rpc.View = cfg.View
+	return &elementsBackend{rpc: rpc}, nil
+}
+
+func (b *elementsBackend) ListUnspent(minconf, maxconf int64, addresses []string) (UnspentList, error) {
+	return b.rpc.ListUnspent(minconf, maxconf, addresses)
+}
+
+func (b *elementsBackend) GetNewAddress(account string) (string, error) {
+	return b.rpc.GetNewAddress(account)
+}
+
+func (b *elementsBackend) ValidateAddress(address string) (ValidatedAddress, error) {
+	return b.rpc.ValidateAddress(address)
+}
+
+func (b *elementsBackend) BlindRawTransaction(hexstring string, inputBlinder, inputAmount, inputAsset, inputAssetBlinder []string) (string, error) {
+	return b.rpc.RawBlindRawTransaction(hexstring, inputBlinder, inputAmount, inputAsset, inputAssetBlinder)
+}
+
+func (b *elementsBackend) SignRawTransaction(hexstring string) (SignedTransaction, error) {
+	return b.rpc.SignRawTransaction(hexstring)
+}
+
+func (b *elementsBackend) SendRawTransaction(hexstring string) (string, error) {
+	return b.rpc.SendRawTransaction(hexstring)
+}
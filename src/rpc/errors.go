@@ -0,0 +1,139 @@
+// Copyright (c) 2017 DG Lab
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Well-known Bitcoin/Elements JSON-RPC error codes, as returned in the
+// "code" field of a JSON-RPC error object. See bitcoin/src/rpc/protocol.h.
+const (
+	RPC_MISC_ERROR              = -1
+	RPC_TYPE_ERROR              = -3
+	RPC_INVALID_ADDRESS_OR_KEY  = -5
+	RPC_OUT_OF_MEMORY           = -7
+	RPC_INVALID_PARAMETER       = -8
+	RPC_DATABASE_ERROR          = -20
+	RPC_DESERIALIZATION_ERROR   = -22
+	RPC_VERIFY_ERROR            = -25
+	RPC_VERIFY_REJECTED         = -26
+	RPC_VERIFY_ALREADY_IN_CHAIN = -27
+	RPC_IN_WARMUP               = -28
+	RPC_METHOD_DEPRECATED       = -32
+
+	RPC_WALLET_ERROR                = -4
+	RPC_WALLET_INSUFFICIENT_FUNDS   = -6
+	RPC_WALLET_INVALID_LABEL_NAME   = -11
+	RPC_WALLET_KEYPOOL_RAN_OUT      = -12
+	RPC_WALLET_UNLOCK_NEEDED        = -13
+	RPC_WALLET_PASSPHRASE_INCORRECT = -14
+	RPC_WALLET_WRONG_ENC_STATE      = -15
+	RPC_WALLET_ENCRYPTION_FAILED    = -16
+	RPC_WALLET_ALREADY_UNLOCKED     = -17
+)
+
+// Error implements error on *RpcError so it can be returned directly from
+// Rpc.Request instead of being flattened into a generic fmt.Errorf string.
+func (e *RpcError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// Is lets errors.Is(err, rpc.ErrWalletUnlockNeeded) (and similar sentinels)
+// match any *RpcError with the same Code, regardless of Message.
+func (e *RpcError) Is(target error) bool {
+	t, ok := target.(*RpcError)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+// Sentinel errors for the most common transient/actionable codes, for use
+// with errors.Is.
+var (
+	ErrWalletUnlockNeeded   = &RpcError{Code: RPC_WALLET_UNLOCK_NEEDED}
+	ErrInWarmup             = &RpcError{Code: RPC_IN_WARMUP}
+	ErrVerifyAlreadyInChain = &RpcError{Code: RPC_VERIFY_ALREADY_IN_CHAIN}
+)
+
+// httpStatusError is returned when the daemon's HTTP layer itself failed
+// (as opposed to a well-formed JSON-RPC error response).
+type httpStatusError struct {
+	Status string
+	Code   int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("http status %s", e.Status)
+}
+
+// RetryPolicy configures Rpc.RequestWithRetry's exponential backoff.
+type RetryPolicy struct {
+	MaxAttempts int           // default 5
+	BaseDelay   time.Duration // default 500ms
+	MaxDelay    time.Duration // default 10s
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 5
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = 500 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = 10 * time.Second
+	}
+	return p
+}
+
+// isTransient reports whether err is worth retrying: the daemon is still
+// warming up, its HTTP layer returned a 5xx, or the connection was
+// refused outright (e.g. elementsd not accepting connections yet).
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrInWarmup) {
+		return true
+	}
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500
+	}
+	return strings.Contains(err.Error(), "connection refused")
+}
+
+// RequestWithRetry is like Rpc.Request but retries transient failures
+// (RPC_IN_WARMUP, 5xx, connection refused) with exponential backoff and
+// jitter. A non-transient error, including RPC_WALLET_UNLOCK_NEEDED, is
+// returned immediately so the caller can prompt for a passphrase.
+func (rpc *Rpc) RequestWithRetry(policy RetryPolicy, method string, params ...interface{}) (RpcResponse, error) {
+	policy = policy.withDefaults()
+	delay := policy.BaseDelay
+	var res RpcResponse
+	var err error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		res, err = rpc.Request(method, params...)
+		if !isTransient(err) {
+			return res, err
+		}
+		if attempt == policy.MaxAttempts {
+			break
+		}
+		if delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+		jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+		time.Sleep(delay + jitter)
+		delay *= 2
+	}
+	return res, err
+}
@@ -0,0 +1,26 @@
+// Copyright (c) 2017 DG Lab
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package rpc
+
+func init() {
+	Register("liquid", newLiquidBackend)
+}
+
+// liquidBackend is the Backend for the Liquid Network. Liquid is an
+// Elements sidechain and speaks the same RPC surface, so it simply
+// reuses elementsBackend; the separate registration exists so that the
+// demo can select it explicitly (and so any Liquid-specific behaviour
+// can be layered on here later without touching callers).
+type liquidBackend struct {
+	*elementsBackend
+}
+
+func newLiquidBackend(cfg Config) (Backend, error) {
+	backend, err := newElementsBackend(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &liquidBackend{backend.(*elementsBackend)}, nil
+}
@@ -0,0 +1,105 @@
+// Typed wrappers for the Elements RPCs this demo calls directly. The
+// parameter names and result fields below were discovered by running
+// cmd/rpcgen against a live elementsd, then hand-typed and wired up to
+// reuse the existing hand-written result types (UnspentList,
+// ValidatedAddress, SignedTransaction, RawTransaction, Balance, ...)
+// instead of the generic per-method structs rpcgen scaffolds on its own.
+// Edit this file directly; rpcgen only produces a starting point, never
+// the final source (see cmd/rpcgen's package doc).
+//
+// There is no automated check that this file still matches a given
+// elementsd's RPC surface — cmd/rpcgen's package doc explains why that
+// isn't wired into the build. If elementsd adds/changes a method this
+// file wraps, re-run rpcgen and update the affected wrapper by hand.
+
+package rpc
+
+// IssueAssetResult is the Result of the "issueasset" RPC.
+type IssueAssetResult struct {
+	Txid    string `json:"txid"`    // (string) the transaction id for the issuance
+	Entropy string `json:"entropy"` // (string) the entropy used to generate the asset tag
+	Asset   string `json:"asset"`   // (string) the asset id for the asset
+	Token   string `json:"token"`   // (string) the asset id for the reissuance token
+	Vin     int64  `json:"vin"`     // (numeric) the input position of the issuance in the transaction
+}
+
+// ReissueAssetResult is the Result of the "reissueasset" RPC.
+type ReissueAssetResult struct {
+	Txid string `json:"txid"` // (string) the transaction id for the reissuance
+	Vin  int64  `json:"vin"`  // (numeric) the input position of the reissuance in the transaction
+}
+
+// rawblindrawtransaction has no struct result: it returns a single hex
+// string, so RawBlindRawTransaction below returns that string directly
+// rather than introducing a one-field struct.
+
+// ListUnspent wraps the "listunspent" RPC.
+func (rpc *Rpc) ListUnspent(minconf, maxconf int64, addresses []string) (UnspentList, error) {
+	params := []interface{}{minconf, maxconf, addresses}
+	var result UnspentList
+	_, err := rpc.RequestAndUnmarshalResult(&result, "listunspent", params...)
+	return result, err
+}
+
+// GetNewAddress wraps the "getnewaddress" RPC.
+func (rpc *Rpc) GetNewAddress(account string) (string, error) {
+	str, _, err := rpc.RequestAndCastString("getnewaddress", account)
+	return str, err
+}
+
+// ValidateAddress wraps the "validateaddress" RPC.
+func (rpc *Rpc) ValidateAddress(address string) (ValidatedAddress, error) {
+	var result ValidatedAddress
+	_, err := rpc.RequestAndUnmarshalResult(&result, "validateaddress", address)
+	return result, err
+}
+
+// RawBlindRawTransaction wraps the "rawblindrawtransaction" RPC, which
+// blinds a raw transaction given the blinding factors and amounts for
+// its own inputs, returning the blinded transaction as a hex string.
+func (rpc *Rpc) RawBlindRawTransaction(hexstring string, inputBlinder, inputAmount, inputAsset, inputAssetBlinder []string) (string, error) {
+	params := []interface{}{hexstring, inputBlinder, inputAmount, inputAsset, inputAssetBlinder}
+	str, _, err := rpc.RequestAndCastString("rawblindrawtransaction", params...)
+	return str, err
+}
+
+// SignRawTransaction wraps the "signrawtransaction" RPC.
+func (rpc *Rpc) SignRawTransaction(hexstring string) (SignedTransaction, error) {
+	var result SignedTransaction
+	_, err := rpc.RequestAndUnmarshalResult(&result, "signrawtransaction", hexstring)
+	return result, err
+}
+
+// SendRawTransaction wraps the "sendrawtransaction" RPC.
+func (rpc *Rpc) SendRawTransaction(hexstring string) (string, error) {
+	str, _, err := rpc.RequestAndCastString("sendrawtransaction", hexstring)
+	return str, err
+}
+
+// GetTransaction wraps the "gettransaction" RPC.
+func (rpc *Rpc) GetTransaction(txid string) (RawTransaction, error) {
+	var result RawTransaction
+	_, err := rpc.RequestAndUnmarshalResult(&result, "gettransaction", txid)
+	return result, err
+}
+
+// GetBalance wraps the "getbalance" RPC.
+func (rpc *Rpc) GetBalance(account string, minconf int64) (Balance, error) {
+	var result Balance
+	_, err := rpc.RequestAndUnmarshalResult(&result, "getbalance", account, minconf)
+	return result, err
+}
+
+// IssueAsset wraps the "issueasset" RPC.
+func (rpc *Rpc) IssueAsset(assetAmount, tokenAmount float64, blind bool) (IssueAssetResult, error) {
+	var result IssueAssetResult
+	_, err := rpc.RequestAndUnmarshalResult(&result, "issueasset", assetAmount, tokenAmount, blind)
+	return result, err
+}
+
+// ReissueAsset wraps the "reissueasset" RPC.
+func (rpc *Rpc) ReissueAsset(asset string, assetAmount float64) (ReissueAssetResult, error) {
+	var result ReissueAssetResult
+	_, err := rpc.RequestAndUnmarshalResult(&result, "reissueasset", asset, assetAmount)
+	return result, err
+}
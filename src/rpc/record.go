@@ -0,0 +1,33 @@
+// Copyright (c) 2017 DG Lab
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package rpc
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// recordedCall is one line of a Rpc.RecordPath file: a request paired
+// with the response it got back, for later replay by rpc/rpctest.
+type recordedCall struct {
+	Request  *RpcRequest `json:"request"`
+	Response RpcResponse `json:"response"`
+}
+
+// appendRecord appends one recordedCall as a JSON line to path, creating
+// the file if needed.
+func appendRecord(path string, req *RpcRequest, res RpcResponse) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	bs, err := json.Marshal(recordedCall{Request: req, Response: res})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(bs, '\n'))
+	return err
+}
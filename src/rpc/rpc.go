@@ -7,6 +7,7 @@ package rpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -112,12 +113,28 @@ type SignedTransaction struct {
 }
 
 type Rpc struct {
-	Url  string
-	User string
-	Pass string
-	View bool
+	Url    string
+	User   string
+	Pass   string
+	View   bool
+	Client *http.Client
+
+	// RecordPath, when non-empty, makes every request/response pair
+	// append a JSON line {"request":...,"response":...} to the named
+	// file, for later replay via rpc/rpctest.
+	RecordPath string
+}
+
+// RpcCall is a single method/params pair for use with Rpc.Batch.
+type RpcCall struct {
+	Method string
+	Params []interface{}
 }
 
+// defaultClient is used when an Rpc was constructed with NewRpc and never
+// had its Client field overridden.
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
 type RpcRequest struct {
 	Jsonrpc string        `json:"jsonrpc,"`
 	Id      string        `json:"id,"`
@@ -181,7 +198,29 @@ func NewRpc(url, user, pass string) *Rpc {
 	return rpc
 }
 
+// NewRpcWithClient is like NewRpc but lets the caller supply an
+// *http.Client, e.g. to configure keep-alive, TLS or a custom timeout
+// instead of the package default.
+func NewRpcWithClient(url, user, pass string, client *http.Client) *Rpc {
+	rpc := NewRpc(url, user, pass)
+	rpc.Client = client
+	return rpc
+}
+
+func (rpc *Rpc) httpClient() *http.Client {
+	if rpc.Client != nil {
+		return rpc.Client
+	}
+	return defaultClient
+}
+
 func (rpc *Rpc) Request(method string, params ...interface{}) (RpcResponse, error) {
+	return rpc.RequestContext(context.Background(), method, params...)
+}
+
+// RequestContext is identical to Request but honours ctx cancellation and
+// deadlines for the underlying HTTP round-trip.
+func (rpc *Rpc) RequestContext(ctx context.Context, method string, params ...interface{}) (RpcResponse, error) {
 	var res RpcResponse
 	if len(params) == 0 {
 		params = []interface{}{}
@@ -192,10 +231,13 @@ func (rpc *Rpc) Request(method string, params ...interface{}) (RpcResponse, erro
 	if rpc.View {
 		fmt.Printf("%s\n", bs)
 	}
-	client := &http.Client{}
-	hreq, _ := http.NewRequest("POST", rpc.Url, bytes.NewBuffer(bs))
+	hreq, err := http.NewRequest("POST", rpc.Url, bytes.NewBuffer(bs))
+	if err != nil {
+		return res, err
+	}
+	hreq = hreq.WithContext(ctx)
 	hreq.SetBasicAuth(rpc.User, rpc.Pass)
-	hres, err := client.Do(hreq)
+	hres, err := rpc.httpClient().Do(hreq)
 	if err != nil {
 		return res, err
 	}
@@ -206,11 +248,116 @@ func (rpc *Rpc) Request(method string, params ...interface{}) (RpcResponse, erro
 	}
 	err = json.Unmarshal(body, &res)
 	if err != nil || hres.StatusCode != http.StatusOK || res.Id != id {
+		// A well-formed JSON-RPC error reply for this request is an
+		// *RpcError whatever its HTTP status, not an HTTP-layer failure.
+		if err == nil && res.Id == id && res.Error != nil {
+			if rpc.RecordPath != "" {
+				if rerr := appendRecord(rpc.RecordPath, req, res); rerr != nil && rpc.View {
+					fmt.Printf("record: %v\n", rerr)
+				}
+			}
+			rerr, _ := res.UnmarshalError()
+			return res, &rerr
+		}
+		if hres.StatusCode >= 500 {
+			return res, &httpStatusError{Status: hres.Status, Code: hres.StatusCode}
+		}
 		return res, fmt.Errorf("status:%v, error:%v, body:%s reqid:%v, resid:%v", hres.Status, err, body, id, res.Id)
 	}
+	if rpc.RecordPath != "" {
+		if rerr := appendRecord(rpc.RecordPath, req, res); rerr != nil && rpc.View {
+			fmt.Printf("record: %v\n", rerr)
+		}
+	}
+	if res.Error != nil {
+		rerr, _ := res.UnmarshalError()
+		return res, &rerr
+	}
 	return res, nil
 }
 
+// BatchResult pairs one batch item's RpcResponse with its Err, the
+// *RpcError decoded from RpcResponse.Error (nil if the call succeeded).
+// This gives batch callers the same errors.Is-able *RpcError that
+// Request/RequestContext return, instead of requiring a manual
+// RpcResponse.UnmarshalError call per item.
+type BatchResult struct {
+	RpcResponse
+	Err error
+}
+
+// Batch issues calls as a single JSON-RPC 2.0 batch request (one HTTP
+// POST carrying a JSON array body) and returns the results in the same
+// order as calls, correlated by their Id regardless of the order the
+// daemon answers them in.
+func (rpc *Rpc) Batch(calls ...RpcCall) ([]BatchResult, error) {
+	return rpc.BatchContext(context.Background(), calls...)
+}
+
+// BatchContext is like Batch but honours ctx cancellation and deadlines.
+func (rpc *Rpc) BatchContext(ctx context.Context, calls ...RpcCall) ([]BatchResult, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+	reqs := make([]*RpcRequest, len(calls))
+	ids := make([]string, len(calls))
+	for i, call := range calls {
+		params := call.Params
+		if params == nil {
+			params = []interface{}{}
+		}
+		id := fmt.Sprintf("%d-%d", time.Now().UnixNano(), i)
+		reqs[i] = &RpcRequest{"1.0", id, call.Method, params}
+		ids[i] = id
+	}
+	bs, _ := json.Marshal(reqs)
+	if rpc.View {
+		fmt.Printf("%s\n", bs)
+	}
+	hreq, err := http.NewRequest("POST", rpc.Url, bytes.NewBuffer(bs))
+	if err != nil {
+		return nil, err
+	}
+	hreq = hreq.WithContext(ctx)
+	hreq.SetBasicAuth(rpc.User, rpc.Pass)
+	hres, err := rpc.httpClient().Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer hres.Body.Close()
+	body, _ := ioutil.ReadAll(hres.Body)
+	if rpc.View {
+		fmt.Printf("%d, %s\n", hres.StatusCode, body)
+	}
+	var raw []RpcResponse
+	if err := json.Unmarshal(body, &raw); err != nil || hres.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status:%v, error:%v, body:%s", hres.Status, err, body)
+	}
+	byId := make(map[string]RpcResponse, len(raw))
+	for _, res := range raw {
+		byId[res.Id] = res
+	}
+	results := make([]BatchResult, len(calls))
+	for i, id := range ids {
+		res, ok := byId[id]
+		if !ok {
+			return nil, fmt.Errorf("batch response missing id:%v", id)
+		}
+		if rpc.RecordPath != "" {
+			if rerr := appendRecord(rpc.RecordPath, reqs[i], res); rerr != nil && rpc.View {
+				fmt.Printf("record: %v\n", rerr)
+			}
+		}
+		result := BatchResult{RpcResponse: res}
+		if res.Error != nil {
+			rerr, _ := res.UnmarshalError()
+			result.Err = &rerr
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
 func (rpc *Rpc) RequestAndUnmarshalResult(result interface{}, method string, params ...interface{}) (RpcResponse, error) {
 	res, err := rpc.Request(method, params...)
 	if err != nil {
@@ -0,0 +1,135 @@
+// Copyright (c) 2017 DG Lab
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package rpctest_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/uniblockchain/confidential-assets-demo/src/rpc"
+	"github.com/uniblockchain/confidential-assets-demo/src/rpc/rpctest"
+)
+
+func TestServerRequest(t *testing.T) {
+	srv := rpctest.NewServer([]rpctest.Fixture{
+		{Method: "getbalance", Response: rpc.RpcResponse{Result: 1.5}},
+	})
+	defer srv.Close()
+
+	client := srv.Rpc()
+	balance, _, err := client.RequestAndCastNumber("getbalance")
+	if err != nil {
+		t.Fatalf("getbalance: %v", err)
+	}
+	if balance != 1.5 {
+		t.Fatalf("getbalance = %v, want 1.5", balance)
+	}
+}
+
+func TestServerBatch(t *testing.T) {
+	srv := rpctest.NewServer([]rpctest.Fixture{
+		{Method: "getbalance", Response: rpc.RpcResponse{Result: 2.0}},
+		{Method: "listunspent", Response: rpc.RpcResponse{Result: []interface{}{}}},
+	})
+	defer srv.Close()
+
+	client := srv.Rpc()
+	results, err := client.Batch(
+		rpc.RpcCall{Method: "getbalance"},
+		rpc.RpcCall{Method: "listunspent"},
+	)
+	if err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Batch returned %d results, want 2", len(results))
+	}
+	if balance, ok := results[0].Result.(float64); !ok || balance != 2.0 {
+		t.Fatalf("results[0].Result = %v, want 2.0", results[0].Result)
+	}
+	var unspent []interface{}
+	if err := results[1].UnmarshalResult(&unspent); err != nil {
+		t.Fatalf("results[1].UnmarshalResult: %v", err)
+	}
+	if len(unspent) != 0 {
+		t.Fatalf("unspent = %v, want empty", unspent)
+	}
+}
+
+func TestServerBatchError(t *testing.T) {
+	srv := rpctest.NewServer([]rpctest.Fixture{
+		{Method: "getbalance", Response: rpc.RpcResponse{
+			Error: map[string]interface{}{"code": rpc.RPC_WALLET_UNLOCK_NEEDED, "message": "wallet locked"},
+		}},
+		{Method: "listunspent", Response: rpc.RpcResponse{Result: []interface{}{}}},
+	})
+	defer srv.Close()
+
+	client := srv.Rpc()
+	results, err := client.Batch(
+		rpc.RpcCall{Method: "getbalance"},
+		rpc.RpcCall{Method: "listunspent"},
+	)
+	if err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+	if !errors.Is(results[0].Err, rpc.ErrWalletUnlockNeeded) {
+		t.Fatalf("results[0].Err = %v, want errors.Is match for ErrWalletUnlockNeeded", results[0].Err)
+	}
+	if results[1].Err != nil {
+		t.Fatalf("results[1].Err = %v, want nil", results[1].Err)
+	}
+}
+
+func TestRecordAndReplayBatch(t *testing.T) {
+	srv := rpctest.NewServer([]rpctest.Fixture{
+		{Method: "getbalance", Response: rpc.RpcResponse{Result: 3.0}},
+		{Method: "listunspent", Response: rpc.RpcResponse{Result: []interface{}{}}},
+	})
+	defer srv.Close()
+
+	client := srv.Rpc()
+	recordPath := filepath.Join(t.TempDir(), "record.jsonl")
+	client.RecordPath = recordPath
+
+	if _, err := client.Batch(
+		rpc.RpcCall{Method: "getbalance"},
+		rpc.RpcCall{Method: "listunspent"},
+	); err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+
+	fixtures, err := rpctest.LoadFixtures(recordPath)
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	if len(fixtures) != 2 {
+		t.Fatalf("LoadFixtures returned %d fixtures, want 2 (one per batched call)", len(fixtures))
+	}
+	if fixtures[0].Method != "getbalance" || fixtures[1].Method != "listunspent" {
+		t.Fatalf("fixtures = %+v, want getbalance then listunspent", fixtures)
+	}
+
+	replay := rpctest.NewServer(fixtures)
+	defer replay.Close()
+	balance, _, err := replay.Rpc().RequestAndCastNumber("getbalance")
+	if err != nil {
+		t.Fatalf("replayed getbalance: %v", err)
+	}
+	if balance != 3.0 {
+		t.Fatalf("replayed getbalance = %v, want 3.0", balance)
+	}
+}
+
+func TestServerUnknownMethod(t *testing.T) {
+	srv := rpctest.NewServer(nil)
+	defer srv.Close()
+
+	_, err := srv.Rpc().Request("listunspent")
+	if err == nil {
+		t.Fatal("expected an error for a method with no fixture queued")
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2017 DG Lab
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+// Package zmqsub is a companion to rpc that subscribes to the ZMQ
+// endpoints Elements/Bitcoin expose (hashblock, hashtx, rawblock, rawtx)
+// so applications can react to new confidential transactions without
+// polling listunspent. It lives in its own package, separate from rpc,
+// because it depends on github.com/pebbe/zmq4, a cgo binding to libzmq:
+// building or vetting this package requires libzmq's headers and
+// pkg-config file installed (e.g. `apt install libzmq3-dev` or `brew
+// install zmq`), and callers that only need the JSON-RPC client or
+// rpc/rpctest should not have to pay for that.
+package zmqsub
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"syscall"
+	"time"
+
+	zmq "github.com/pebbe/zmq4"
+)
+
+// Well-known Elements/Bitcoin ZMQ publisher topics. See
+// https://github.com/ElementsProject/elements/blob/master/doc/zmq.md
+const (
+	TopicHashBlock = "hashblock"
+	TopicHashTx    = "hashtx"
+	TopicRawBlock  = "rawblock"
+	TopicRawTx     = "rawtx"
+)
+
+// Subscriber connects to a single `zmqpub*` endpoint exposed by
+// elementsd/bitcoind and fans out the notifications it receives as typed
+// Go channels. Every multipart message published by the daemon carries a
+// topic frame, a payload frame and a trailing 4-byte little-endian
+// sequence number per topic; Subscriber uses that sequence number to
+// detect dropped messages and reports each gap on Gap. A gap does not
+// trigger a reconnect; the socket is redialed only after a socket error.
+type Subscriber struct {
+	Endpoint string
+	Topics   []string
+
+	HashBlock chan string
+	HashTx    chan string
+	RawBlock  chan []byte
+	RawTx     chan []byte
+
+	// Gap is sent the topic name whenever a sequence-number gap is
+	// detected for it, i.e. one or more notifications were missed. A gap
+	// detected while Gap's buffer is full is not reported.
+	Gap chan string
+	// Errors carries socket/reconnect errors; it is never closed.
+	Errors chan error
+
+	quit    chan struct{}
+	lastSeq map[string]uint32
+}
+
+// New dials endpoint (e.g. "tcp://127.0.0.1:18332") and subscribes to
+// topics (any of TopicHashBlock, TopicHashTx, TopicRawBlock, TopicRawTx).
+// If topics is empty it subscribes to all four. The returned channels are
+// populated from a background goroutine that reconnects if the socket
+// errors out, waiting 1s before the first retry and doubling the wait
+// after each failed attempt, up to 32s.
+func New(endpoint string, topics ...string) (*Subscriber, error) {
+	if len(topics) == 0 {
+		topics = []string{TopicHashBlock, TopicHashTx, TopicRawBlock, TopicRawTx}
+	}
+	sub := &Subscriber{
+		Endpoint:  endpoint,
+		Topics:    topics,
+		HashBlock: make(chan string, 32),
+		HashTx:    make(chan string, 32),
+		RawBlock:  make(chan []byte, 8),
+		RawTx:     make(chan []byte, 32),
+		Gap:       make(chan string, 8),
+		Errors:    make(chan error, 8),
+		quit:      make(chan struct{}),
+		lastSeq:   make(map[string]uint32),
+	}
+	go sub.run()
+	return sub, nil
+}
+
+// Close stops the subscriber's background goroutine and releases its
+// socket. It does not close the notification channels, since a goroutine
+// may still be selecting on them.
+func (sub *Subscriber) Close() {
+	close(sub.quit)
+}
+
+func (sub *Subscriber) run() {
+	backoff := time.Second
+	for {
+		select {
+		case <-sub.quit:
+			return
+		default:
+		}
+		if err := sub.connectAndReceive(); err != nil {
+			select {
+			case sub.Errors <- err:
+			case <-sub.quit:
+				return
+			}
+			select {
+			case <-sub.quit:
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+			continue
+		}
+		backoff = time.Second
+	}
+}
+
+func (sub *Subscriber) connectAndReceive() error {
+	socket, err := zmq.NewSocket(zmq.SUB)
+	if err != nil {
+		return fmt.Errorf("zmq.NewSocket: %v", err)
+	}
+	defer socket.Close()
+
+	if err := socket.Connect(sub.Endpoint); err != nil {
+		return fmt.Errorf("zmq.Connect(%s): %v", sub.Endpoint, err)
+	}
+	for _, topic := range sub.Topics {
+		if err := socket.SetSubscribe(topic); err != nil {
+			return fmt.Errorf("zmq.SetSubscribe(%s): %v", topic, err)
+		}
+	}
+	// Bound RecvMessageBytes so the loop wakes up periodically to check
+	// sub.quit even when the endpoint is idle, instead of blocking on the
+	// socket forever.
+	if err := socket.SetRcvtimeo(time.Second); err != nil {
+		return fmt.Errorf("zmq.SetRcvtimeo: %v", err)
+	}
+
+	for {
+		select {
+		case <-sub.quit:
+			return nil
+		default:
+		}
+		parts, err := socket.RecvMessageBytes(0)
+		if err != nil {
+			if zmq.AsErrno(err) == zmq.Errno(syscall.EAGAIN) { // recv timeout, loop and recheck quit
+				continue
+			}
+			return fmt.Errorf("zmq.RecvMessageBytes: %v", err)
+		}
+		if len(parts) != 3 {
+			continue
+		}
+		topic := string(parts[0])
+		body := parts[1]
+		seq := binary.LittleEndian.Uint32(parts[2])
+		sub.checkSequence(topic, seq)
+		if stopped := sub.dispatch(topic, body); stopped {
+			return nil
+		}
+	}
+}
+
+func (sub *Subscriber) checkSequence(topic string, seq uint32) {
+	last, seen := sub.lastSeq[topic]
+	sub.lastSeq[topic] = seq
+	if seen && seq != last+1 {
+		select {
+		case sub.Gap <- topic:
+		default:
+		}
+	}
+}
+
+// dispatch delivers body on topic's channel, reporting stopped=true if
+// sub.quit closed first instead. Every send is select-able against quit
+// so that a consumer which stops draining a channel cannot leave the
+// background goroutine blocked on a full, unread channel after Close().
+func (sub *Subscriber) dispatch(topic string, body []byte) (stopped bool) {
+	switch topic {
+	case TopicHashBlock:
+		select {
+		case sub.HashBlock <- hex.EncodeToString(body):
+		case <-sub.quit:
+			return true
+		}
+	case TopicHashTx:
+		select {
+		case sub.HashTx <- hex.EncodeToString(body):
+		case <-sub.quit:
+			return true
+		}
+	case TopicRawBlock:
+		select {
+		case sub.RawBlock <- body:
+		case <-sub.quit:
+			return true
+		}
+	case TopicRawTx:
+		select {
+		case sub.RawTx <- body:
+		case <-sub.quit:
+			return true
+		}
+	}
+	return false
+}